@@ -0,0 +1,159 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// AgentWeights controls how a service instance is weighted in DNS SRV
+// answers depending on its health.
+type AgentWeights struct {
+	Passing int
+	Warning int
+}
+
+// AgentServiceCheck describes a health check registered together with a
+// service. Exactly one of the check kinds (TTL, HTTP, TCP, GRPC) should be set.
+type AgentServiceCheck struct {
+	CheckID string `json:",omitempty"`
+	Name    string `json:",omitempty"`
+	Notes   string `json:",omitempty"`
+	Status  string `json:",omitempty"`
+
+	Interval string `json:",omitempty"`
+	Timeout  string `json:",omitempty"`
+	TTL      string `json:",omitempty"`
+
+	HTTP          string              `json:",omitempty"`
+	Method        string              `json:",omitempty"`
+	Header        map[string][]string `json:",omitempty"`
+	TLSSkipVerify bool                `json:",omitempty"`
+	TCP           string              `json:",omitempty"`
+	GRPC          string              `json:",omitempty"`
+	GRPCUseTLS    bool                `json:",omitempty"`
+
+	// DeregisterCriticalServiceAfter removes the service once the check has
+	// been critical for this long.
+	DeregisterCriticalServiceAfter string `json:",omitempty"`
+}
+
+// AgentServiceChecks is a list of checks registered with a service.
+type AgentServiceChecks []*AgentServiceCheck
+
+// AgentServiceRegistration is used to register a service with the local agent.
+type AgentServiceRegistration struct {
+	ID                string             `json:",omitempty"`
+	Name              string             `json:",omitempty"`
+	Tags              []string           `json:",omitempty"`
+	Port              int                `json:",omitempty"`
+	Address           string             `json:",omitempty"`
+	EnableTagOverride bool               `json:",omitempty"`
+	Meta              map[string]string  `json:",omitempty"`
+	Weights           *AgentWeights      `json:",omitempty"`
+	Check             *AgentServiceCheck `json:",omitempty"`
+	Checks            AgentServiceChecks `json:",omitempty"`
+}
+
+// AgentService is a service as known to the local agent.
+type AgentService struct {
+	ID                string
+	Service           string
+	Tags              []string
+	Meta              map[string]string
+	Port              int
+	Address           string
+	Weights           AgentWeights
+	EnableTagOverride bool
+	CreateIndex       uint64 `json:",omitempty"`
+	ModifyIndex       uint64 `json:",omitempty"`
+	Datacenter        string `json:",omitempty"`
+}
+
+// Agent is used to query the local agent's endpoints.
+type Agent struct {
+	c *Client
+}
+
+// Agent returns a handle to the agent endpoints.
+func (c *Client) Agent() *Agent {
+	return &Agent{c}
+}
+
+// ServiceRegister registers a new service with the local agent.
+func (a *Agent) ServiceRegister(service *AgentServiceRegistration) error {
+	return a.ServiceRegisterOpts(service, nil)
+}
+
+// ServiceRegisterOpts registers a new service with the local agent using the
+// given write options.
+func (a *Agent) ServiceRegisterOpts(service *AgentServiceRegistration, q *WriteOptions) error {
+	_, err := a.c.write(http.MethodPut, "/v1/agent/service/register", nil, service, nil, q)
+	return err
+}
+
+// ServiceDeregister removes a service from the local agent.
+func (a *Agent) ServiceDeregister(serviceID string) error {
+	return a.ServiceDeregisterOpts(serviceID, nil)
+}
+
+// ServiceDeregisterOpts removes a service from the local agent using the
+// given write options.
+func (a *Agent) ServiceDeregisterOpts(serviceID string, q *WriteOptions) error {
+	_, err := a.c.write(http.MethodPut, "/v1/agent/service/deregister/"+serviceID, nil, nil, nil, q)
+	return err
+}
+
+// Services returns the services registered with the local agent, keyed by
+// service ID.
+func (a *Agent) Services() (map[string]*AgentService, error) {
+	return a.ServicesOpts(nil)
+}
+
+// ServicesOpts returns the services registered with the local agent, keyed
+// by service ID, using the given query options.
+func (a *Agent) ServicesOpts(q *QueryOptions) (map[string]*AgentService, error) {
+	var out map[string]*AgentService
+	if _, err := a.c.query("/v1/agent/services", nil, &out, q); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PassTTL marks a TTL check as passing.
+func (a *Agent) PassTTL(checkID, note string) error {
+	return a.PassTTLOpts(checkID, note, nil)
+}
+
+// PassTTLOpts marks a TTL check as passing using the given write options.
+func (a *Agent) PassTTLOpts(checkID, note string, q *WriteOptions) error {
+	return a.updateTTL(checkID, "pass", note, q)
+}
+
+// WarnTTL marks a TTL check as warning.
+func (a *Agent) WarnTTL(checkID, note string) error {
+	return a.WarnTTLOpts(checkID, note, nil)
+}
+
+// WarnTTLOpts marks a TTL check as warning using the given write options.
+func (a *Agent) WarnTTLOpts(checkID, note string, q *WriteOptions) error {
+	return a.updateTTL(checkID, "warn", note, q)
+}
+
+// FailTTL marks a TTL check as critical.
+func (a *Agent) FailTTL(checkID, note string) error {
+	return a.FailTTLOpts(checkID, note, nil)
+}
+
+// FailTTLOpts marks a TTL check as critical using the given write options.
+func (a *Agent) FailTTLOpts(checkID, note string, q *WriteOptions) error {
+	return a.updateTTL(checkID, "fail", note, q)
+}
+
+func (a *Agent) updateTTL(checkID, status, note string, q *WriteOptions) error {
+	params := url.Values{}
+	if note != "" {
+		params.Set("note", note)
+	}
+	_, err := a.c.write(http.MethodPut, "/v1/agent/check/"+status+"/"+checkID, params, nil, nil, q)
+	return err
+}
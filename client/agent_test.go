@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAgent_ServiceRegister(t *testing.T) {
+	var (
+		method, path, contentType string
+		got                       AgentServiceRegistration
+	)
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+	})
+
+	reg := &AgentServiceRegistration{
+		ID:   "web-1",
+		Name: "web",
+		Tags: []string{"v1"},
+		Port: 8080,
+		Check: &AgentServiceCheck{
+			TTL: "10s",
+		},
+	}
+	if err := c.Agent().ServiceRegister(reg); err != nil {
+		t.Fatalf("ServiceRegister: %v", err)
+	}
+
+	if method != http.MethodPut || path != "/v1/agent/service/register" {
+		t.Fatalf("request = %s %s, want PUT /v1/agent/service/register", method, path)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	if got.ID != "web-1" || got.Name != "web" || got.Port != 8080 || len(got.Tags) != 1 || got.Tags[0] != "v1" {
+		t.Errorf("unexpected registration: %+v", got)
+	}
+	if got.Check == nil || got.Check.TTL != "10s" {
+		t.Errorf("unexpected check: %+v", got.Check)
+	}
+}
+
+func TestAgent_ServiceRegister_OmitsEmptyFields(t *testing.T) {
+	var raw map[string]any
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&raw)
+	})
+
+	if err := c.Agent().ServiceRegister(&AgentServiceRegistration{Name: "web"}); err != nil {
+		t.Fatalf("ServiceRegister: %v", err)
+	}
+	if len(raw) != 1 || raw["Name"] != "web" {
+		t.Fatalf("body = %v, want only Name", raw)
+	}
+}
+
+func TestAgent_ServiceDeregister(t *testing.T) {
+	var method, path string
+	var bodyLen int64
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path, bodyLen = r.Method, r.URL.EscapedPath(), r.ContentLength
+	})
+
+	if err := c.Agent().ServiceDeregister("web 1"); err != nil {
+		t.Fatalf("ServiceDeregister: %v", err)
+	}
+	if method != http.MethodPut || path != "/v1/agent/service/deregister/web%201" {
+		t.Fatalf("request = %s %s, want PUT /v1/agent/service/deregister/web%%201", method, path)
+	}
+	if bodyLen != 0 {
+		t.Errorf("content length = %d, want 0", bodyLen)
+	}
+}
+
+func TestAgent_Services(t *testing.T) {
+	var query string
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"web-1":{"ID":"web-1","Service":"web","Port":8080}}`))
+	})
+
+	services, err := c.Agent().ServicesOpts(&QueryOptions{Filter: `Service == "web"`})
+	if err != nil {
+		t.Fatalf("ServicesOpts: %v", err)
+	}
+	if query != "filter=Service+%3D%3D+%22web%22" {
+		t.Errorf("query = %q", query)
+	}
+	if svc := services["web-1"]; svc == nil || svc.Service != "web" || svc.Port != 8080 {
+		t.Fatalf("unexpected services: %+v", services)
+	}
+}
+
+func TestAgent_UpdateTTL(t *testing.T) {
+	var method, path, query, token string
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path, query = r.Method, r.URL.Path, r.URL.RawQuery
+		token = r.Header.Get("X-Consul-Token")
+	})
+
+	cases := []struct {
+		update func(checkID, note string, q *WriteOptions) error
+		status string
+	}{
+		{c.Agent().PassTTLOpts, "pass"},
+		{c.Agent().WarnTTLOpts, "warn"},
+		{c.Agent().FailTTLOpts, "fail"},
+	}
+	for _, tc := range cases {
+		if err := tc.update("service:web-1", "all good", &WriteOptions{Token: "secret"}); err != nil {
+			t.Fatalf("%s: %v", tc.status, err)
+		}
+		if method != http.MethodPut || path != "/v1/agent/check/"+tc.status+"/service:web-1" {
+			t.Errorf("request = %s %s", method, path)
+		}
+		if query != "note=all+good" {
+			t.Errorf("query = %q, want note=all+good", query)
+		}
+		if token != "secret" {
+			t.Errorf("token = %q, want secret", token)
+		}
+	}
+
+	if err := c.Agent().PassTTL("service:web-1", ""); err != nil {
+		t.Fatalf("PassTTL: %v", err)
+	}
+	if query != "" {
+		t.Errorf("query = %q, want empty", query)
+	}
+}
+
+func TestAgent_UpdateTTL_Context(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent with a cancelled context")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.Agent().PassTTLOpts("service:web-1", "", (&WriteOptions{}).WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,444 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HTTPAddrEnvName is the environment variable that overrides the agent address.
+	HTTPAddrEnvName = "BATATA_HTTP_ADDR"
+
+	// HTTPTokenEnvName is the environment variable that sets the ACL token.
+	HTTPTokenEnvName = "BATATA_HTTP_TOKEN"
+
+	// HTTPSSLEnvName is the environment variable that enables HTTPS.
+	HTTPSSLEnvName = "BATATA_HTTP_SSL"
+
+	// DefaultAddress is the agent address used when none is configured.
+	DefaultAddress = "127.0.0.1:8500"
+)
+
+// Config is used to configure a Client.
+type Config struct {
+	// Address is the host:port of the batata agent. A scheme prefix
+	// ("http://" or "https://") is also accepted and overrides Scheme.
+	Address string
+
+	// Scheme is the URI scheme, "http" or "https".
+	Scheme string
+
+	// Datacenter is sent with every request when set.
+	Datacenter string
+
+	// Token is the ACL token sent with every request when set.
+	Token string
+
+	// WaitTime limits how long blocking queries wait when the caller does
+	// not set QueryOptions.WaitTime.
+	WaitTime time.Duration
+
+	// HTTPClient is used for all requests. http.DefaultClient is used when nil.
+	HTTPClient *http.Client
+}
+
+// DefaultConfig returns a Config pointing at the local agent, with overrides
+// taken from the BATATA_HTTP_* environment variables.
+func DefaultConfig() *Config {
+	config := &Config{
+		Address: DefaultAddress,
+		Scheme:  "http",
+	}
+
+	if addr := os.Getenv(HTTPAddrEnvName); addr != "" {
+		config.Address = addr
+	}
+	if token := os.Getenv(HTTPTokenEnvName); token != "" {
+		config.Token = token
+	}
+	if ssl := os.Getenv(HTTPSSLEnvName); ssl != "" {
+		if enabled, err := strconv.ParseBool(ssl); err == nil && enabled {
+			config.Scheme = "https"
+		}
+	}
+
+	return config
+}
+
+// Client provides access to the batata HTTP API.
+type Client struct {
+	config  Config
+	baseURL *url.URL
+}
+
+// NewClient returns a Client for config. An empty Address, Scheme or Token
+// falls back to DefaultConfig, so the BATATA_HTTP_* environment variables
+// still apply, and a nil HTTPClient falls back to http.DefaultClient.
+func NewClient(config *Config) (*Client, error) {
+	defaults := DefaultConfig()
+	if config == nil {
+		config = defaults
+	}
+
+	c := *config
+	if c.Address == "" {
+		c.Address = defaults.Address
+	}
+	if c.Scheme == "" {
+		c.Scheme = defaults.Scheme
+	}
+	if c.Token == "" {
+		c.Token = defaults.Token
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = http.DefaultClient
+	}
+
+	if strings.Contains(c.Address, "://") {
+		u, err := url.Parse(c.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", c.Address, err)
+		}
+		if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			return nil, fmt.Errorf("address %q must not contain a path", c.Address)
+		}
+		c.Scheme = u.Scheme
+		c.Address = u.Host
+	}
+	if c.Scheme != "http" && c.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q", c.Scheme)
+	}
+	if c.Address == "" || strings.ContainsAny(c.Address, "/?#") {
+		return nil, fmt.Errorf("invalid address %q", c.Address)
+	}
+
+	return &Client{
+		config:  c,
+		baseURL: &url.URL{Scheme: c.Scheme, Host: c.Address},
+	}, nil
+}
+
+// QueryOptions are parameters shared by read requests.
+type QueryOptions struct {
+	// Datacenter overrides Config.Datacenter.
+	Datacenter string
+
+	// Token overrides Config.Token.
+	Token string
+
+	// AllowStale lets any server answer the read, not just the leader.
+	AllowStale bool
+
+	// RequireConsistent forces a fully consistent read.
+	RequireConsistent bool
+
+	// WaitIndex turns the request into a blocking query that returns once
+	// the index moves past this value or WaitTime elapses.
+	WaitIndex uint64
+
+	// WaitTime bounds a blocking query.
+	WaitTime time.Duration
+
+	// Filter is a filter expression applied server side.
+	Filter string
+
+	ctx context.Context
+}
+
+// WithContext returns a copy of o that uses ctx for the request.
+func (o *QueryOptions) WithContext(ctx context.Context) *QueryOptions {
+	o2 := new(QueryOptions)
+	if o != nil {
+		*o2 = *o
+	}
+	o2.ctx = ctx
+	return o2
+}
+
+// WriteOptions are parameters shared by write requests.
+type WriteOptions struct {
+	// Datacenter overrides Config.Datacenter.
+	Datacenter string
+
+	// Token overrides Config.Token.
+	Token string
+
+	ctx context.Context
+}
+
+// WithContext returns a copy of o that uses ctx for the request.
+func (o *WriteOptions) WithContext(ctx context.Context) *WriteOptions {
+	o2 := new(WriteOptions)
+	if o != nil {
+		*o2 = *o
+	}
+	o2.ctx = ctx
+	return o2
+}
+
+// QueryMeta carries response metadata from read requests.
+type QueryMeta struct {
+	// LastIndex should be passed as QueryOptions.WaitIndex to block on
+	// the next change.
+	LastIndex uint64
+
+	// LastContact is the time since the answering server last heard from
+	// the leader.
+	LastContact time.Duration
+
+	// KnownLeader reports whether the answering server knew of a leader.
+	KnownLeader bool
+
+	// RequestTime is the round trip time of the request.
+	RequestTime time.Duration
+}
+
+// WriteMeta carries response metadata from write requests.
+type WriteMeta struct {
+	// RequestTime is the round trip time of the request.
+	RequestTime time.Duration
+}
+
+// StatusError is returned when the server answers with an unexpected status.
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("unexpected response code: %d (%s)", e.Code, e.Body)
+}
+
+type request struct {
+	method string
+	path   string
+	params url.Values
+	header http.Header
+	body   any
+	ctx    context.Context
+}
+
+func (c *Client) newRequest(method, path string) *request {
+	r := &request{
+		method: method,
+		path:   path,
+		params: make(url.Values),
+		header: make(http.Header),
+		ctx:    context.Background(),
+	}
+	if c.config.Datacenter != "" {
+		r.params.Set("dc", c.config.Datacenter)
+	}
+	if c.config.Token != "" {
+		r.header.Set("X-Consul-Token", c.config.Token)
+	}
+	return r
+}
+
+// newQueryRequest returns a GET request for path with q and the extra params
+// applied. Config.WaitTime is used when q does not set a wait of its own.
+func (c *Client) newQueryRequest(path string, params url.Values, q *QueryOptions) *request {
+	r := c.newRequest(http.MethodGet, path)
+	r.setQueryOptions(q)
+	if !r.params.Has("wait") && c.config.WaitTime != 0 {
+		r.params.Set("wait", durToMsec(c.config.WaitTime))
+	}
+	for k, v := range params {
+		r.params[k] = v
+	}
+	return r
+}
+
+func (r *request) setQueryOptions(q *QueryOptions) {
+	if q == nil {
+		return
+	}
+	if q.Datacenter != "" {
+		r.params.Set("dc", q.Datacenter)
+	}
+	if q.Token != "" {
+		r.header.Set("X-Consul-Token", q.Token)
+	}
+	if q.AllowStale {
+		r.params.Set("stale", "")
+	}
+	if q.RequireConsistent {
+		r.params.Set("consistent", "")
+	}
+	if q.WaitIndex != 0 {
+		r.params.Set("index", strconv.FormatUint(q.WaitIndex, 10))
+	}
+	if q.WaitTime != 0 {
+		r.params.Set("wait", durToMsec(q.WaitTime))
+	}
+	if q.Filter != "" {
+		r.params.Set("filter", q.Filter)
+	}
+	if q.ctx != nil {
+		r.ctx = q.ctx
+	}
+}
+
+func (r *request) setWriteOptions(q *WriteOptions) {
+	if q == nil {
+		return
+	}
+	if q.Datacenter != "" {
+		r.params.Set("dc", q.Datacenter)
+	}
+	if q.Token != "" {
+		r.header.Set("X-Consul-Token", q.Token)
+	}
+	if q.ctx != nil {
+		r.ctx = q.ctx
+	}
+}
+
+// doRequest sends r and returns the response and its round trip time. The
+// caller owns the response body.
+func (c *Client) doRequest(r *request) (time.Duration, *http.Response, error) {
+	u := *c.baseURL
+	u.Path = r.path
+	u.RawQuery = r.params.Encode()
+
+	var body io.Reader
+	switch b := r.body.(type) {
+	case nil:
+	case []byte:
+		body = bytes.NewReader(b)
+	default:
+		buf, err := json.Marshal(b)
+		if err != nil {
+			return 0, nil, err
+		}
+		body = bytes.NewReader(buf)
+		r.header.Set("Content-Type", "application/json")
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, r.method, u.String(), body)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header = r.header
+
+	start := time.Now()
+	resp, err := c.config.HTTPClient.Do(req)
+	return time.Since(start), resp, err
+}
+
+// maxErrorBodySize caps how much of an error response is kept in StatusError.
+const maxErrorBodySize = 64 << 10
+
+// requireOK closes the body and returns a StatusError unless resp has a 200
+// status. At most maxErrorBodySize bytes of the body are read into the error.
+func requireOK(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	defer closeResponseBody(resp)
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(resp.Body, maxErrorBodySize)); err != nil {
+		return StatusError{Code: resp.StatusCode, Body: "failed to read body: " + err.Error()}
+	}
+	return StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(buf.String())}
+}
+
+// closeResponseBody drains and closes the body so the connection can be reused.
+func closeResponseBody(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func decodeBody(resp *http.Response, out any) error {
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// query performs a GET on path with the extra params and decodes the JSON
+// response into out.
+func (c *Client) query(path string, params url.Values, out any, q *QueryOptions) (*QueryMeta, error) {
+	rtt, resp, err := c.doRequest(c.newQueryRequest(path, params, q))
+	if err != nil {
+		return nil, err
+	}
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	qm := &QueryMeta{RequestTime: rtt}
+	if err := parseQueryMeta(resp, qm); err != nil {
+		return nil, err
+	}
+	if err := decodeBody(resp, out); err != nil {
+		return nil, err
+	}
+	return qm, nil
+}
+
+// write performs a request with the extra params and body in and, when out is
+// non-nil, decodes the JSON response into it. A []byte body is sent as is,
+// anything else is encoded as JSON.
+func (c *Client) write(method, path string, params url.Values, in, out any, q *WriteOptions) (*WriteMeta, error) {
+	r := c.newRequest(method, path)
+	r.setWriteOptions(q)
+	for k, v := range params {
+		r.params[k] = v
+	}
+	r.body = in
+	rtt, resp, err := c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	if out != nil {
+		if err := decodeBody(resp, out); err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+	return &WriteMeta{RequestTime: rtt}, nil
+}
+
+func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
+	header := resp.Header
+
+	if index := header.Get("X-Consul-Index"); index != "" {
+		v, err := strconv.ParseUint(index, 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse X-Consul-Index: %w", err)
+		}
+		q.LastIndex = v
+	}
+
+	if contact := header.Get("X-Consul-LastContact"); contact != "" {
+		v, err := strconv.ParseUint(contact, 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse X-Consul-LastContact: %w", err)
+		}
+		q.LastContact = time.Duration(v) * time.Millisecond
+	}
+
+	q.KnownLeader = header.Get("X-Consul-KnownLeader") == "true"
+
+	return nil
+}
+
+func durToMsec(dur time.Duration) string {
+	ms := dur / time.Millisecond
+	if dur > 0 && ms == 0 {
+		ms = 1
+	}
+	return fmt.Sprintf("%dms", ms)
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// makeClient starts a test server backed by handler and returns a Client
+// pointed at it.
+func makeClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(&Config{Address: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+func TestNewClient_Scheme(t *testing.T) {
+	t.Setenv(HTTPAddrEnvName, "")
+	t.Setenv(HTTPSSLEnvName, "")
+
+	cases := []struct {
+		name    string
+		address string
+		scheme  string
+		wantURL string
+		wantErr bool
+	}{
+		{name: "default", address: "", wantURL: "http://" + DefaultAddress},
+		{name: "host port", address: "10.0.0.1:8500", wantURL: "http://10.0.0.1:8500"},
+		{name: "explicit scheme", address: "10.0.0.1:8500", scheme: "https", wantURL: "https://10.0.0.1:8500"},
+		{name: "https prefix", address: "https://10.0.0.1:8501", wantURL: "https://10.0.0.1:8501"},
+		{name: "prefix overrides scheme", address: "http://10.0.0.1:8500", scheme: "https", wantURL: "http://10.0.0.1:8500"},
+		{name: "trailing slash", address: "http://10.0.0.1:8500/", wantURL: "http://10.0.0.1:8500"},
+		{name: "path", address: "http://10.0.0.1:8500/v1", wantErr: true},
+		{name: "unix prefix", address: "unix:///var/run/batata.sock", wantErr: true},
+		{name: "unknown scheme", address: "10.0.0.1:8500", scheme: "ftp", wantErr: true},
+		{name: "missing host", address: "http://", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewClient(&Config{Address: tc.address, Scheme: tc.scheme})
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got client for %s", c.baseURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			if got := c.baseURL.String(); got != tc.wantURL {
+				t.Fatalf("base URL = %q, want %q", got, tc.wantURL)
+			}
+		})
+	}
+}
+
+func TestNewClient_SSLEnv(t *testing.T) {
+	t.Setenv(HTTPAddrEnvName, "10.0.0.1:8501")
+	t.Setenv(HTTPSSLEnvName, "true")
+
+	c, err := NewClient(nil)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if got := c.baseURL.String(); got != "https://10.0.0.1:8501" {
+		t.Fatalf("base URL = %q, want https://10.0.0.1:8501", got)
+	}
+
+	t.Setenv(HTTPSSLEnvName, "false")
+	c, err = NewClient(nil)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if c.baseURL.Scheme != "http" {
+		t.Fatalf("scheme = %q, want http", c.baseURL.Scheme)
+	}
+}
+
+func TestNewClient_TokenFallback(t *testing.T) {
+	t.Setenv(HTTPTokenEnvName, "env-token")
+
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Consul-Token"))
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{Address: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.Agent().Services(); err != nil {
+		t.Fatalf("Services: %v", err)
+	}
+
+	c, err = NewClient(&Config{Address: srv.URL, Token: "explicit"})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.Agent().ServicesOpts(&QueryOptions{Token: "per-call"}); err != nil {
+		t.Fatalf("ServicesOpts: %v", err)
+	}
+	if _, err := c.Agent().Services(); err != nil {
+		t.Fatalf("Services: %v", err)
+	}
+
+	want := []string{"env-token", "per-call", "explicit"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("tokens = %v, want %v", got, want)
+	}
+}
+
+func TestQueryMeta(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Consul-Index", "42")
+		w.Header().Set("X-Consul-LastContact", "150")
+		w.Header().Set("X-Consul-KnownLeader", "true")
+		w.Write([]byte("[]"))
+	})
+
+	_, qm, err := c.Health().State(HealthAny, nil)
+	if err != nil {
+		t.Fatalf("State: %v", err)
+	}
+	if qm.LastIndex != 42 {
+		t.Errorf("LastIndex = %d, want 42", qm.LastIndex)
+	}
+	if qm.LastContact != 150*time.Millisecond {
+		t.Errorf("LastContact = %s, want 150ms", qm.LastContact)
+	}
+	if !qm.KnownLeader {
+		t.Error("KnownLeader = false, want true")
+	}
+}
+
+func TestQueryMeta_InvalidIndex(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Consul-Index", "not-a-number")
+		w.Write([]byte("[]"))
+	})
+
+	if _, _, err := c.Health().State(HealthAny, nil); err == nil {
+		t.Fatal("expected error for malformed X-Consul-Index")
+	}
+}
+
+func TestQueryOptions_Params(t *testing.T) {
+	var query string
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte("[]"))
+	})
+
+	_, _, err := c.Health().State(HealthAny, &QueryOptions{
+		Datacenter: "dc2",
+		WaitIndex:  7,
+		WaitTime:   2 * time.Second,
+		Filter:     `Status == "passing"`,
+	})
+	if err != nil {
+		t.Fatalf("State: %v", err)
+	}
+
+	want := "dc=dc2&filter=Status+%3D%3D+%22passing%22&index=7&wait=2000ms"
+	if query != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("Permission denied\n"))
+	})
+
+	_, err := c.Agent().Services()
+
+	var statusErr StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if statusErr.Code != http.StatusForbidden || statusErr.Body != "Permission denied" {
+		t.Fatalf("unexpected error: %+v", statusErr)
+	}
+}
+
+func TestStatusError_BodyLimit(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(strings.Repeat("x", 2*maxErrorBodySize)))
+	})
+
+	err := c.Agent().ServiceDeregister("web-1")
+
+	var statusErr StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if len(statusErr.Body) != maxErrorBodySize {
+		t.Fatalf("body length = %d, want %d", len(statusErr.Body), maxErrorBodySize)
+	}
+}
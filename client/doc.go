@@ -0,0 +1,24 @@
+// Package client is a Go client for the batata HTTP API.
+//
+// It covers the pieces downstream services need most often: registering and
+// deregistering services with the local agent, looking up service health and
+// reading and writing KV entries. The wire format follows batata's
+// Consul-compatible /v1 endpoints, so existing service definitions carry over
+// unchanged.
+//
+// Import it as:
+//
+//	import "github.com/easynet-cn/batata/client"
+//
+// A minimal registration looks like:
+//
+//	c, err := client.NewClient(client.DefaultConfig())
+//	if err != nil {
+//		return err
+//	}
+//	err = c.Agent().ServiceRegister(&client.AgentServiceRegistration{
+//		ID:   "web-1",
+//		Name: "web",
+//		Port: 8080,
+//	})
+package client
@@ -0,0 +1,3 @@
+module github.com/easynet-cn/batata/client
+
+go 1.22
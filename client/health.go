@@ -0,0 +1,158 @@
+package client
+
+import (
+	"net/url"
+)
+
+const (
+	// HealthAny matches checks in any state when querying by state.
+	HealthAny = "any"
+
+	// HealthPassing is the status of a check that is succeeding.
+	HealthPassing = "passing"
+
+	// HealthWarning is the status of a check that is degraded but still
+	// considered usable.
+	HealthWarning = "warning"
+
+	// HealthCritical is the status of a check that is failing.
+	HealthCritical = "critical"
+
+	// HealthMaint is the status of a check placed in maintenance mode.
+	HealthMaint = "maintenance"
+)
+
+// HealthCheck is a single check as reported by the health endpoints.
+type HealthCheck struct {
+	Node        string
+	CheckID     string
+	Name        string
+	Status      string
+	Notes       string
+	Output      string
+	ServiceID   string
+	ServiceName string
+	ServiceTags []string
+	Type        string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// HealthChecks is a list of checks.
+type HealthChecks []*HealthCheck
+
+// AggregatedStatus returns the worst status among the checks, with
+// maintenance taking precedence over everything else. An empty list is
+// passing. It returns "" if any check has a status the client does not
+// recognize, so an unknown state is never reported as healthy.
+func (c HealthChecks) AggregatedStatus() string {
+	var warning, critical, maintenance bool
+	for _, check := range c {
+		switch check.Status {
+		case HealthPassing:
+		case HealthWarning:
+			warning = true
+		case HealthCritical:
+			critical = true
+		case HealthMaint:
+			maintenance = true
+		default:
+			return ""
+		}
+	}
+	switch {
+	case maintenance:
+		return HealthMaint
+	case critical:
+		return HealthCritical
+	case warning:
+		return HealthWarning
+	default:
+		return HealthPassing
+	}
+}
+
+// Node is a catalog node as embedded in health responses.
+type Node struct {
+	ID         string
+	Node       string
+	Address    string
+	Datacenter string
+	Meta       map[string]string
+}
+
+// ServiceEntry is a service instance together with its node and checks.
+type ServiceEntry struct {
+	Node    *Node
+	Service *AgentService
+	Checks  HealthChecks
+}
+
+// Health is used to query the health endpoints.
+type Health struct {
+	c *Client
+}
+
+// Health returns a handle to the health endpoints.
+func (c *Client) Health() *Health {
+	return &Health{c}
+}
+
+// Service returns the instances of service, optionally restricted to those
+// carrying tag and to those whose checks are all passing.
+func (h *Health) Service(service, tag string, passingOnly bool, q *QueryOptions) ([]*ServiceEntry, *QueryMeta, error) {
+	var tags []string
+	if tag != "" {
+		tags = []string{tag}
+	}
+	return h.ServiceMultipleTags(service, tags, passingOnly, q)
+}
+
+// ServiceMultipleTags is like Service but matches instances carrying all of
+// tags.
+func (h *Health) ServiceMultipleTags(service string, tags []string, passingOnly bool, q *QueryOptions) ([]*ServiceEntry, *QueryMeta, error) {
+	params := url.Values{}
+	for _, tag := range tags {
+		params.Add("tag", tag)
+	}
+	if passingOnly {
+		params.Set("passing", "1")
+	}
+
+	var out []*ServiceEntry
+	qm, err := h.c.query("/v1/health/service/"+service, params, &out, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
+
+// Checks returns the checks of all instances of service.
+func (h *Health) Checks(service string, q *QueryOptions) (HealthChecks, *QueryMeta, error) {
+	var out HealthChecks
+	qm, err := h.c.query("/v1/health/checks/"+service, nil, &out, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
+
+// Node returns the checks registered on node.
+func (h *Health) Node(node string, q *QueryOptions) (HealthChecks, *QueryMeta, error) {
+	var out HealthChecks
+	qm, err := h.c.query("/v1/health/node/"+node, nil, &out, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
+
+// State returns the checks in state, which is one of the Health* constants.
+func (h *Health) State(state string, q *QueryOptions) (HealthChecks, *QueryMeta, error) {
+	var out HealthChecks
+	qm, err := h.c.query("/v1/health/state/"+state, nil, &out, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, qm, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestHealth_Service(t *testing.T) {
+	var path string
+	var params url.Values
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path, params = r.URL.Path, r.URL.Query()
+		w.Header().Set("X-Consul-Index", "12")
+		w.Write([]byte(`[{
+			"Node": {"Node": "node-1", "Address": "10.0.0.1"},
+			"Service": {"ID": "web-1", "Service": "web", "Port": 8080},
+			"Checks": [{"CheckID": "serfHealth", "Status": "passing"}]
+		}]`))
+	})
+
+	entries, qm, err := c.Health().Service("web", "v1", true, nil)
+	if err != nil {
+		t.Fatalf("Service: %v", err)
+	}
+
+	if path != "/v1/health/service/web" {
+		t.Errorf("path = %q", path)
+	}
+	if params.Get("passing") != "1" {
+		t.Errorf("passing = %q, want 1", params.Get("passing"))
+	}
+	if tags := params["tag"]; len(tags) != 1 || tags[0] != "v1" {
+		t.Errorf("tag = %v, want [v1]", tags)
+	}
+	if qm.LastIndex != 12 {
+		t.Errorf("LastIndex = %d, want 12", qm.LastIndex)
+	}
+	if len(entries) != 1 || entries[0].Service.ID != "web-1" || entries[0].Node.Node != "node-1" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestHealth_Service_NoFilters(t *testing.T) {
+	var query string
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte("[]"))
+	})
+
+	if _, _, err := c.Health().Service("web", "", false, nil); err != nil {
+		t.Fatalf("Service: %v", err)
+	}
+	if query != "" {
+		t.Fatalf("query = %q, want empty", query)
+	}
+}
+
+func TestHealth_ServiceMultipleTags(t *testing.T) {
+	var params url.Values
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params = r.URL.Query()
+		w.Write([]byte("[]"))
+	})
+
+	if _, _, err := c.Health().ServiceMultipleTags("web", []string{"v1", "primary"}, false, nil); err != nil {
+		t.Fatalf("ServiceMultipleTags: %v", err)
+	}
+	if tags := params["tag"]; len(tags) != 2 || tags[0] != "v1" || tags[1] != "primary" {
+		t.Errorf("tag = %v, want [v1 primary]", tags)
+	}
+	if params.Has("passing") {
+		t.Error("passing should not be set")
+	}
+}
+
+func TestHealthChecks_AggregatedStatus(t *testing.T) {
+	checks := func(statuses ...string) HealthChecks {
+		var out HealthChecks
+		for _, s := range statuses {
+			out = append(out, &HealthCheck{Status: s})
+		}
+		return out
+	}
+
+	cases := []struct {
+		checks HealthChecks
+		want   string
+	}{
+		{nil, HealthPassing},
+		{checks(HealthPassing, HealthPassing), HealthPassing},
+		{checks(HealthPassing, HealthWarning), HealthWarning},
+		{checks(HealthWarning, HealthCritical), HealthCritical},
+		{checks(HealthCritical, HealthMaint), HealthMaint},
+		{checks(HealthPassing, ""), ""},
+		{checks(HealthPassing, "unknown"), ""},
+		{checks(HealthMaint, "unknown"), ""},
+	}
+	for _, tc := range cases {
+		if got := tc.checks.AggregatedStatus(); got != tc.want {
+			t.Errorf("AggregatedStatus(%v) = %q, want %q", tc.checks, got, tc.want)
+		}
+	}
+}
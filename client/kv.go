@@ -0,0 +1,206 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// KVPair is a single KV entry.
+type KVPair struct {
+	Key         string
+	CreateIndex uint64
+	ModifyIndex uint64
+	LockIndex   uint64
+	Flags       uint64
+	Value       []byte
+	Session     string
+}
+
+// KVPairs is a list of KV entries.
+type KVPairs []*KVPair
+
+// validateKey rejects keys with a leading slash, which would address a
+// different key than the caller meant once joined onto /v1/kv/.
+func validateKey(key string) error {
+	if strings.HasPrefix(key, "/") {
+		return fmt.Errorf("invalid key %q: must not begin with a '/'", key)
+	}
+	return nil
+}
+
+// KV is used to access the KV store. Keys and prefixes must not begin with
+// a '/'.
+type KV struct {
+	c *Client
+}
+
+// KV returns a handle to the KV endpoints.
+func (c *Client) KV() *KV {
+	return &KV{c}
+}
+
+// Get returns the entry for key, or nil if it does not exist.
+func (k *KV) Get(key string, q *QueryOptions) (*KVPair, *QueryMeta, error) {
+	var entries KVPairs
+	qm, err := k.getInternal(key, nil, &entries, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(entries) == 0 {
+		return nil, qm, nil
+	}
+	return entries[0], qm, nil
+}
+
+// List returns all entries whose key starts with prefix.
+func (k *KV) List(prefix string, q *QueryOptions) (KVPairs, *QueryMeta, error) {
+	params := url.Values{}
+	params.Set("recurse", "")
+
+	var entries KVPairs
+	qm, err := k.getInternal(prefix, params, &entries, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, qm, nil
+}
+
+// Keys returns the keys starting with prefix. A non-empty separator stops
+// the listing at the first occurrence of it after the prefix, which gives a
+// directory-style view of the tree.
+func (k *KV) Keys(prefix, separator string, q *QueryOptions) ([]string, *QueryMeta, error) {
+	params := url.Values{}
+	params.Set("keys", "")
+	if separator != "" {
+		params.Set("separator", separator)
+	}
+
+	var keys []string
+	qm, err := k.getInternal(prefix, params, &keys, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys, qm, nil
+}
+
+// getInternal performs a KV read. A missing key is not an error; out is left
+// untouched in that case.
+func (k *KV) getInternal(key string, params url.Values, out any, q *QueryOptions) (*QueryMeta, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	rtt, resp, err := k.c.doRequest(k.c.newQueryRequest("/v1/kv/"+key, params, q))
+	if err != nil {
+		return nil, err
+	}
+
+	notFound := resp.StatusCode == http.StatusNotFound
+	if !notFound {
+		if err := requireOK(resp); err != nil {
+			return nil, err
+		}
+	}
+	defer closeResponseBody(resp)
+
+	qm := &QueryMeta{RequestTime: rtt}
+	if err := parseQueryMeta(resp, qm); err != nil {
+		return nil, err
+	}
+	if notFound {
+		return qm, nil
+	}
+	if err := decodeBody(resp, out); err != nil {
+		return nil, err
+	}
+	return qm, nil
+}
+
+// Put writes p.Value and p.Flags to p.Key.
+func (k *KV) Put(p *KVPair, q *WriteOptions) (*WriteMeta, error) {
+	params := url.Values{}
+	if p.Flags != 0 {
+		params.Set("flags", strconv.FormatUint(p.Flags, 10))
+	}
+	_, wm, err := k.put(p, params, q)
+	return wm, err
+}
+
+// CAS writes p only if the entry's ModifyIndex still equals p.ModifyIndex. A
+// ModifyIndex of zero only succeeds if the key does not exist yet.
+func (k *KV) CAS(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
+	params := url.Values{}
+	if p.Flags != 0 {
+		params.Set("flags", strconv.FormatUint(p.Flags, 10))
+	}
+	params.Set("cas", strconv.FormatUint(p.ModifyIndex, 10))
+	return k.put(p, params, q)
+}
+
+// Acquire writes p and takes the lock on it for p.Session.
+func (k *KV) Acquire(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
+	params := url.Values{}
+	if p.Flags != 0 {
+		params.Set("flags", strconv.FormatUint(p.Flags, 10))
+	}
+	params.Set("acquire", p.Session)
+	return k.put(p, params, q)
+}
+
+// Release writes p and releases the lock held on it by p.Session.
+func (k *KV) Release(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
+	params := url.Values{}
+	if p.Flags != 0 {
+		params.Set("flags", strconv.FormatUint(p.Flags, 10))
+	}
+	params.Set("release", p.Session)
+	return k.put(p, params, q)
+}
+
+func (k *KV) put(p *KVPair, params url.Values, q *WriteOptions) (bool, *WriteMeta, error) {
+	if err := validateKey(p.Key); err != nil {
+		return false, nil, err
+	}
+	var ok bool
+	wm, err := k.c.write(http.MethodPut, "/v1/kv/"+p.Key, params, p.Value, &ok, q)
+	if err != nil {
+		return false, nil, err
+	}
+	return ok, wm, nil
+}
+
+// Delete removes key.
+func (k *KV) Delete(key string, q *WriteOptions) (*WriteMeta, error) {
+	_, wm, err := k.delete(key, nil, q)
+	return wm, err
+}
+
+// DeleteCAS removes p.Key only if the entry's ModifyIndex still equals
+// p.ModifyIndex.
+func (k *KV) DeleteCAS(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
+	params := url.Values{}
+	params.Set("cas", strconv.FormatUint(p.ModifyIndex, 10))
+	return k.delete(p.Key, params, q)
+}
+
+// DeleteTree removes every key starting with prefix.
+func (k *KV) DeleteTree(prefix string, q *WriteOptions) (*WriteMeta, error) {
+	params := url.Values{}
+	params.Set("recurse", "")
+	_, wm, err := k.delete(prefix, params, q)
+	return wm, err
+}
+
+func (k *KV) delete(key string, params url.Values, q *WriteOptions) (bool, *WriteMeta, error) {
+	if err := validateKey(key); err != nil {
+		return false, nil, err
+	}
+	var ok bool
+	wm, err := k.c.write(http.MethodDelete, "/v1/kv/"+key, params, nil, &ok, q)
+	if err != nil {
+		return false, nil, err
+	}
+	return ok, wm, nil
+}
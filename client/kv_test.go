@@ -0,0 +1,241 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestKV_Get(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/app/config" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Header().Set("X-Consul-Index", "5")
+		w.Write([]byte(`[{"Key":"app/config","Flags":3,"Value":"aGVsbG8=","ModifyIndex":5}]`))
+	})
+
+	pair, qm, err := c.KV().Get("app/config", nil)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if pair == nil || string(pair.Value) != "hello" || pair.Flags != 3 || pair.ModifyIndex != 5 {
+		t.Fatalf("unexpected pair: %+v", pair)
+	}
+	if qm.LastIndex != 5 {
+		t.Errorf("LastIndex = %d, want 5", qm.LastIndex)
+	}
+}
+
+func TestKV_Get_NotFound(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Consul-Index", "9")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	pair, qm, err := c.KV().Get("missing", nil)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if pair != nil {
+		t.Fatalf("pair = %+v, want nil", pair)
+	}
+	if qm == nil || qm.LastIndex != 9 {
+		t.Fatalf("QueryMeta = %+v, want LastIndex 9", qm)
+	}
+}
+
+func TestKV_Get_StatusErrorBeforeMeta(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Consul-Index", "not-a-number")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("leader unavailable"))
+	})
+
+	_, _, err := c.KV().Get("app/config", nil)
+
+	var statusErr StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected StatusError, got %v", err)
+	}
+	if statusErr.Code != http.StatusInternalServerError || statusErr.Body != "leader unavailable" {
+		t.Fatalf("unexpected error: %+v", statusErr)
+	}
+}
+
+func TestKV_DefaultWaitTime(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{Address: srv.URL, WaitTime: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	q := &QueryOptions{WaitIndex: 3}
+	if _, _, err := c.KV().Get("app/config", q); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, _, err := c.KV().List("app/", q); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if _, _, err := c.KV().Get("app/config", &QueryOptions{WaitIndex: 3, WaitTime: time.Second}); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	want := []string{
+		"index=3&wait=5000ms",
+		"index=3&recurse=&wait=5000ms",
+		"index=3&wait=1000ms",
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("queries = %v, want %v", queries, want)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("query %d = %q, want %q", i, queries[i], want[i])
+		}
+	}
+}
+
+func TestKV_ListAndKeys(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Has("recurse"):
+			w.Write([]byte(`[{"Key":"app/a"},{"Key":"app/b"}]`))
+		case q.Has("keys"):
+			if q.Get("separator") != "/" {
+				t.Errorf("separator = %q, want /", q.Get("separator"))
+			}
+			w.Write([]byte(`["app/a","app/dir/"]`))
+		default:
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+	})
+
+	pairs, _, err := c.KV().List("app/", nil)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(pairs) != 2 || pairs[1].Key != "app/b" {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+
+	keys, _, err := c.KV().Keys("app/", "/", nil)
+	if err != nil {
+		t.Fatalf("Keys: %v", err)
+	}
+	if len(keys) != 2 || keys[1] != "app/dir/" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+}
+
+func TestKV_Put(t *testing.T) {
+	var method, query, body string
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, query, body = r.Method, r.URL.RawQuery, string(b)
+		w.Write([]byte("true"))
+	})
+
+	if _, err := c.KV().Put(&KVPair{Key: "app/config", Flags: 3, Value: []byte("raw value")}, nil); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if method != http.MethodPut || query != "flags=3" || body != "raw value" {
+		t.Fatalf("request = %s ?%s %q", method, query, body)
+	}
+}
+
+func TestKV_CAS(t *testing.T) {
+	for _, result := range []string{"true", "false"} {
+		t.Run(result, func(t *testing.T) {
+			var query string
+			c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				w.Write([]byte(result))
+			})
+
+			ok, _, err := c.KV().CAS(&KVPair{Key: "app/config", ModifyIndex: 17, Value: []byte("v")}, nil)
+			if err != nil {
+				t.Fatalf("CAS: %v", err)
+			}
+			if query != "cas=17" {
+				t.Errorf("query = %q, want cas=17", query)
+			}
+			if ok != (result == "true") {
+				t.Fatalf("ok = %v, want %s", ok, result)
+			}
+		})
+	}
+}
+
+func TestKV_DeleteCAS(t *testing.T) {
+	for _, result := range []string{"true", "false"} {
+		t.Run(result, func(t *testing.T) {
+			var method, query string
+			c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				method, query = r.Method, r.URL.RawQuery
+				w.Write([]byte(result))
+			})
+
+			ok, _, err := c.KV().DeleteCAS(&KVPair{Key: "app/config", ModifyIndex: 4}, nil)
+			if err != nil {
+				t.Fatalf("DeleteCAS: %v", err)
+			}
+			if method != http.MethodDelete || query != "cas=4" {
+				t.Errorf("request = %s ?%s, want DELETE ?cas=4", method, query)
+			}
+			if ok != (result == "true") {
+				t.Fatalf("ok = %v, want %s", ok, result)
+			}
+		})
+	}
+}
+
+func TestKV_DeleteTree(t *testing.T) {
+	var method, path, query string
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path, query = r.Method, r.URL.Path, r.URL.RawQuery
+	})
+
+	if _, err := c.KV().DeleteTree("app/", nil); err != nil {
+		t.Fatalf("DeleteTree: %v", err)
+	}
+	if method != http.MethodDelete || path != "/v1/kv/app/" || query != "recurse=" {
+		t.Fatalf("request = %s %s?%s", method, path, query)
+	}
+}
+
+func TestKV_LeadingSlash(t *testing.T) {
+	c := makeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	kv := c.KV()
+
+	if _, _, err := kv.Get("/app", nil); err == nil {
+		t.Error("Get: expected error")
+	}
+	if _, err := kv.Put(&KVPair{Key: "/app"}, nil); err == nil {
+		t.Error("Put: expected error")
+	}
+	if _, _, err := kv.CAS(&KVPair{Key: "/app"}, nil); err == nil {
+		t.Error("CAS: expected error")
+	}
+	if _, err := kv.Delete("/app", nil); err == nil {
+		t.Error("Delete: expected error")
+	}
+	if _, _, err := kv.DeleteCAS(&KVPair{Key: "/app"}, nil); err == nil {
+		t.Error("DeleteCAS: expected error")
+	}
+	if _, err := kv.DeleteTree("/app", nil); err == nil {
+		t.Error("DeleteTree: expected error")
+	}
+}